@@ -1,32 +1,58 @@
 package converter
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"image/png"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/klippa-app/go-pdfium"
+	"github.com/klippa-app/go-pdfium/references"
 	"github.com/klippa-app/go-pdfium/requests"
 	"github.com/klippa-app/go-pdfium/webassembly"
 )
 
+// ProgressFunc is invoked after each page finishes rendering so callers can
+// drive a progress bar. done/total count completed vs. selected pages; page
+// is the page number that just finished (pages may complete out of order
+// when WorkerCount > 1).
+type ProgressFunc func(done, total, page int)
+
 // Config holds the conversion configuration
 type Config struct {
 	InputFile string
 	OutputDir string
-	Format    string // "png" or "jpeg"
-	Quality   int    // JPEG quality (1-100)
-	DPI       int    // Render DPI
-	Pages     string // Page range: "all", "1", "1-5", "1,3,5"
-	Prefix    string // Output filename prefix
+	// Format is a comma-separated list of output formats, e.g. "png" or
+	// "txt,png". Supported values: "png", "jpeg"/"jpg", "txt", "hocr",
+	// "searchable-pdf", "webp", "tiff", "jbig2".
+	Format       string
+	Quality      int    // JPEG quality (1-100)
+	DPI          int    // Render DPI
+	Pages        string // Page range: "all", "1", "1-5", "1,3,5"
+	Prefix       string // Output filename prefix
+	ProgressFunc ProgressFunc
+
+	// MaxWidth and MaxHeight downscale a rendered page, preserving aspect
+	// ratio, if either bound is exceeded. Zero means unbounded.
+	MaxWidth  int
+	MaxHeight int
+	// MaxBytes re-encodes a JPEG page at progressively lower quality until
+	// it fits, down to a floor of quality 20. It has no effect on PNG.
+	MaxBytes int
+
+	// MultiPage writes every selected page of the "tiff" format into a
+	// single multi-page TIFF file instead of one file per page.
+	MultiPage bool
 }
 
 // Result holds the conversion result for a single file
@@ -40,47 +66,116 @@ type Result struct {
 
 // Converter handles PDF to image conversion
 type Converter struct {
-	pool     pdfium.Pool
-	instance pdfium.Pdfium
+	pool        pdfium.Pool
+	workerCount int
 }
 
-// New creates a new Converter instance
-func New() (*Converter, error) {
-	// Initialize the WebAssembly pool
+// New creates a new Converter instance. workerCount sizes the underlying
+// PDFium pool and bounds how many pages Convert renders in parallel; values
+// below 1 are treated as 1 (sequential, the historical behavior).
+func New(workerCount int) (*Converter, error) {
+	if workerCount < 1 {
+		workerCount = 1
+	}
+
+	// Initialize the WebAssembly pool with one PDFium instance per worker.
 	pool, err := webassembly.Init(webassembly.Config{
-		MinIdle:  1,
-		MaxIdle:  1,
-		MaxTotal: 1,
+		MinIdle:  workerCount,
+		MaxIdle:  workerCount,
+		MaxTotal: workerCount,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize PDFium: %w", err)
 	}
 
-	// Get an instance from the pool
-	instance, err := pool.GetInstance(time.Second * 30)
-	if err != nil {
-		pool.Close()
-		return nil, fmt.Errorf("failed to get PDFium instance: %w", err)
-	}
-
 	return &Converter{
-		pool:     pool,
-		instance: instance,
+		pool:        pool,
+		workerCount: workerCount,
 	}, nil
 }
 
 // Close releases resources
 func (c *Converter) Close() {
-	if c.instance != nil {
-		c.instance.Close()
-	}
 	if c.pool != nil {
 		c.pool.Close()
 	}
 }
 
-// Convert performs the PDF to image conversion
-func (c *Converter) Convert(cfg Config) (*Result, error) {
+// ConvertStream renders the pages of a PDF read from r and invokes fn for
+// each one in page order, passing the decoded image straight to the
+// callback instead of writing it to disk. This lets callers compose
+// conversions in shell pipelines or HTTP handlers without touching the
+// filesystem. Rendering is sequential so fn is never called concurrently.
+func (c *Converter) ConvertStream(ctx context.Context, r io.Reader, dpi int, pages string, fn func(pageNum int, img image.Image) error) error {
+	pdfData, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read PDF stream: %w", err)
+	}
+
+	instance, err := c.pool.GetInstance(time.Second * 30)
+	if err != nil {
+		return fmt.Errorf("failed to get PDFium instance: %w", err)
+	}
+	defer instance.Close()
+
+	doc, err := instance.OpenDocument(&requests.OpenDocument{File: &pdfData})
+	if err != nil {
+		return fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer instance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{Document: doc.Document})
+
+	pageCountResp, err := instance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{Document: doc.Document})
+	if err != nil {
+		return fmt.Errorf("failed to get page count: %w", err)
+	}
+
+	pageNums, err := parsePageRange(pages, pageCountResp.PageCount)
+	if err != nil {
+		return fmt.Errorf("invalid page range: %w", err)
+	}
+
+	for _, pageNum := range pageNums {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		renderResp, err := instance.RenderPageInDPI(&requests.RenderPageInDPI{
+			DPI: dpi,
+			Page: requests.Page{
+				ByIndex: &requests.PageByIndex{
+					Document: doc.Document,
+					Index:    pageNum - 1,
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to render page %d: %w", pageNum, err)
+		}
+
+		if err := fn(pageNum, renderResp.Result.Image); err != nil {
+			return fmt.Errorf("callback failed for page %d: %w", pageNum, err)
+		}
+	}
+
+	return nil
+}
+
+// pageJobResult is the outcome of rendering and saving a single page.
+type pageJobResult struct {
+	pageIndex   int // index into the resolved pages slice, for stable ordering
+	pageNum     int
+	outputFiles []string // one entry per requested output format
+	searchable  *searchablePageData
+	tiffImage   image.Image // set when format "tiff" + MultiPage, combined after all pages finish
+	err         error
+}
+
+// Convert performs the PDF to image conversion. Pages are fanned out across
+// up to Converter.workerCount goroutines, each holding its own PDFium
+// instance and document handle; ctx cancellation aborts outstanding work
+// fail-fast. Result.OutputFiles is always ordered by page number regardless
+// of which worker finished first.
+func (c *Converter) Convert(ctx context.Context, cfg Config) (*Result, error) {
 	result := &Result{
 		InputFile:   cfg.InputFile,
 		OutputFiles: []string{},
@@ -94,27 +189,11 @@ func (c *Converter) Convert(cfg Config) (*Result, error) {
 		return result, fmt.Errorf("failed to read PDF file: %w", err)
 	}
 
-	// Open the document
-	doc, err := c.instance.OpenDocument(&requests.OpenDocument{
-		File: &pdfData,
-	})
-	if err != nil {
-		result.Error = fmt.Sprintf("failed to open PDF: %v", err)
-		return result, fmt.Errorf("failed to open PDF: %w", err)
-	}
-	defer c.instance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{
-		Document: doc.Document,
-	})
-
-	// Get page count
-	pageCountResp, err := c.instance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{
-		Document: doc.Document,
-	})
+	totalPages, err := c.pageCount(pdfData)
 	if err != nil {
 		result.Error = fmt.Sprintf("failed to get page count: %v", err)
-		return result, fmt.Errorf("failed to get page count: %w", err)
+		return result, err
 	}
-	totalPages := pageCountResp.PageCount
 
 	// Parse page range
 	pages, err := parsePageRange(cfg.Pages, totalPages)
@@ -144,44 +223,339 @@ func (c *Converter) Convert(cfg Config) (*Result, error) {
 		prefix = strings.TrimSuffix(base, filepath.Ext(base))
 	}
 
-	// Convert each page
-	for _, pageNum := range pages {
-		pageIndex := pageNum - 1 // 0-indexed
+	outputFiles, err := c.renderPages(ctx, pdfData, pages, cfg, outputDir, prefix)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
 
-		// Render page
-		renderResp, err := c.instance.RenderPageInDPI(&requests.RenderPageInDPI{
-			DPI: cfg.DPI,
-			Page: requests.Page{
-				ByIndex: &requests.PageByIndex{
-					Document: doc.Document,
-					Index:    pageIndex,
-				},
-			},
-		})
+	result.OutputFiles = outputFiles
+	result.PageCount = len(pages)
+	result.Success = true
+	return result, nil
+}
+
+// PageCount reports how many pages pdfData contains, without rendering any
+// of them. Callers that need to reject oversized documents up front (for
+// example, a server enforcing a page limit) can check this before Convert.
+func (c *Converter) PageCount(pdfData []byte) (int, error) {
+	return c.pageCount(pdfData)
+}
+
+// ResolvedPageCount reports how many pages the given page-range selection
+// resolves to against pdfData, without rendering any of them. Callers that
+// need a selection to resolve to exactly one page (for example, "-o -"
+// streaming a single page to stdout) can check this before ConvertStream,
+// so a bad selection is rejected before anything is written.
+func (c *Converter) ResolvedPageCount(pdfData []byte, pages string) (int, error) {
+	total, err := c.pageCount(pdfData)
+	if err != nil {
+		return 0, err
+	}
+
+	pageNums, err := parsePageRange(pages, total)
+	if err != nil {
+		return 0, fmt.Errorf("invalid page range: %w", err)
+	}
+	return len(pageNums), nil
+}
+
+// pageCount opens pdfData just long enough to read its page count.
+func (c *Converter) pageCount(pdfData []byte) (int, error) {
+	instance, err := c.pool.GetInstance(time.Second * 30)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get PDFium instance: %w", err)
+	}
+	defer instance.Close()
+
+	doc, err := instance.OpenDocument(&requests.OpenDocument{File: &pdfData})
+	if err != nil {
+		return 0, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer instance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{Document: doc.Document})
+
+	pageCountResp, err := instance.FPDF_GetPageCount(&requests.FPDF_GetPageCount{Document: doc.Document})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get page count: %w", err)
+	}
+	return pageCountResp.PageCount, nil
+}
+
+// renderPages fans pages out across the worker pool and returns the output
+// file paths in page order.
+func (c *Converter) renderPages(ctx context.Context, pdfData []byte, pages []int, cfg Config, outputDir, prefix string) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := c.workerCount
+	if workers > len(pages) {
+		workers = len(pages)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	results := make(chan pageJobResult)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.renderWorker(ctx, pdfData, pages, cfg, outputDir, prefix, jobs, results)
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range pages {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	perPageFiles := make([][]string, len(pages))
+	searchablePages := make([]*searchablePageData, len(pages))
+	tiffPages := make([]image.Image, len(pages))
+	done := 0
+	var firstErr error
+	for r := range results {
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+				cancel()
+			}
+			continue
+		}
+		perPageFiles[r.pageIndex] = r.outputFiles
+		searchablePages[r.pageIndex] = r.searchable
+		tiffPages[r.pageIndex] = r.tiffImage
+		done++
+		if cfg.ProgressFunc != nil {
+			cfg.ProgressFunc(done, len(pages), r.pageNum)
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if done < len(pages) {
+		// Workers stopped early because the caller's ctx was cancelled.
+		return nil, fmt.Errorf("conversion cancelled: %w", ctx.Err())
+	}
+
+	outputFiles := make([]string, 0, len(pages))
+	for _, files := range perPageFiles {
+		outputFiles = append(outputFiles, files...)
+	}
+
+	formats := parseFormats(cfg.Format)
+
+	if containsFormat(formats, formatSearchablePDF) {
+		pdfFile, err := c.writeSearchablePDF(searchablePages, outputDir, prefix)
 		if err != nil {
-			result.Error = fmt.Sprintf("failed to render page %d: %v", pageNum, err)
-			return result, fmt.Errorf("failed to render page %d: %w", pageNum, err)
+			return nil, err
 		}
+		outputFiles = append(outputFiles, pdfFile)
+	}
 
-		// Generate output filename
-		ext := cfg.Format
-		if ext == "jpeg" {
-			ext = "jpg"
+	if containsFormat(formats, formatTIFF) && cfg.MultiPage {
+		tiffFile, err := writeMultipageTIFFFile(tiffPages, cfg.DPI, outputDir, prefix)
+		if err != nil {
+			return nil, err
 		}
-		outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_page_%03d.%s", prefix, pageNum, ext))
+		outputFiles = append(outputFiles, tiffFile)
+	}
+
+	return outputFiles, nil
+}
+
+// renderWorker pulls page indexes off jobs until it is closed (or ctx is
+// cancelled), rendering and saving each one with its own PDFium instance.
+func (c *Converter) renderWorker(ctx context.Context, pdfData []byte, pages []int, cfg Config, outputDir, prefix string, jobs <-chan int, results chan<- pageJobResult) {
+	instance, err := c.pool.GetInstance(time.Second * 30)
+	if err != nil {
+		c.drainWithError(ctx, jobs, results, fmt.Errorf("failed to get PDFium instance: %w", err))
+		return
+	}
+	defer instance.Close()
+
+	doc, err := instance.OpenDocument(&requests.OpenDocument{File: &pdfData})
+	if err != nil {
+		c.drainWithError(ctx, jobs, results, fmt.Errorf("failed to open PDF: %w", err))
+		return
+	}
+	defer instance.FPDF_CloseDocument(&requests.FPDF_CloseDocument{Document: doc.Document})
 
-		// Save image
-		if err := saveImage(renderResp.Result.Image, outputFile, cfg.Format, cfg.Quality); err != nil {
-			result.Error = fmt.Sprintf("failed to save page %d: %v", pageNum, err)
-			return result, fmt.Errorf("failed to save page %d: %w", pageNum, err)
+	formats := parseFormats(cfg.Format)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case idx, ok := <-jobs:
+			if !ok {
+				return
+			}
+			pageNum := pages[idx]
+			r := c.renderOnePage(instance, doc.Document, pageNum, idx, formats, cfg, outputDir, prefix)
+
+			select {
+			case results <- r:
+			case <-ctx.Done():
+				return
+			}
 		}
+	}
+}
 
-		result.OutputFiles = append(result.OutputFiles, outputFile)
+// renderOnePage renders a single page and produces every output the request
+// asked for (images, text, hOCR), plus the data the searchable-pdf format
+// needs once every page has been rendered.
+func (c *Converter) renderOnePage(instance pdfium.Pdfium, document references.FPDF_DOCUMENT, pageNum, pageIndex int, formats []string, cfg Config, outputDir, prefix string) pageJobResult {
+	r := pageJobResult{pageIndex: pageIndex, pageNum: pageNum}
+	page := requests.Page{
+		ByIndex: &requests.PageByIndex{
+			Document: document,
+			Index:    pageNum - 1,
+		},
 	}
 
-	result.PageCount = len(pages)
-	result.Success = true
-	return result, nil
+	needsImage := containsFormat(formats, "png") || containsFormat(formats, "jpeg") || containsFormat(formats, "jpg") ||
+		containsFormat(formats, formatWebP) || containsFormat(formats, formatTIFF) || containsFormat(formats, formatJBIG2) ||
+		containsFormat(formats, formatSearchablePDF) || containsFormat(formats, formatHOCR)
+	needsText := containsFormat(formats, formatTXT) || containsFormat(formats, formatHOCR) || containsFormat(formats, formatSearchablePDF)
+
+	var img image.Image
+	if needsImage {
+		renderResp, err := instance.RenderPageInDPI(&requests.RenderPageInDPI{DPI: cfg.DPI, Page: page})
+		if err != nil {
+			r.err = fmt.Errorf("failed to render page %d: %w", pageNum, err)
+			return r
+		}
+		img = renderResp.Result.Image
+	}
+
+	var words []textWord
+	var pageText string
+	if needsText {
+		pt, err := extractPageText(instance, page, cfg.DPI)
+		if err != nil {
+			r.err = fmt.Errorf("failed to extract text from page %d: %w", pageNum, err)
+			return r
+		}
+		pageText = pt.Text
+		words = pt.Words
+	}
+
+	for _, format := range formats {
+		switch format {
+		case "png", "jpeg", "jpg":
+			ext := format
+			if ext == "jpeg" {
+				ext = "jpg"
+			}
+			outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_page_%03d.%s", prefix, pageNum, ext))
+			thumb := Fit(img, cfg.MaxWidth, cfg.MaxHeight)
+
+			if format != "png" && cfg.MaxBytes > 0 {
+				data, err := fitJPEGToBudget(thumb, cfg.Quality, cfg.MaxBytes)
+				if err != nil {
+					r.err = fmt.Errorf("failed to save page %d: %w", pageNum, err)
+					return r
+				}
+				if err := os.WriteFile(outputFile, data, 0644); err != nil {
+					r.err = fmt.Errorf("failed to save page %d: %w", pageNum, err)
+					return r
+				}
+			} else if err := saveImage(thumb, outputFile, format, cfg.Quality); err != nil {
+				r.err = fmt.Errorf("failed to save page %d: %w", pageNum, err)
+				return r
+			}
+			r.outputFiles = append(r.outputFiles, outputFile)
+		case formatTXT:
+			outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_page_%03d.txt", prefix, pageNum))
+			if err := os.WriteFile(outputFile, []byte(pageText), 0644); err != nil {
+				r.err = fmt.Errorf("failed to write text for page %d: %w", pageNum, err)
+				return r
+			}
+			r.outputFiles = append(r.outputFiles, outputFile)
+		case formatHOCR:
+			outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_page_%03d.hocr", prefix, pageNum))
+			hocr := buildHOCR(pageNum, img.Bounds().Dx(), img.Bounds().Dy(), words)
+			if err := os.WriteFile(outputFile, []byte(hocr), 0644); err != nil {
+				r.err = fmt.Errorf("failed to write hOCR for page %d: %w", pageNum, err)
+				return r
+			}
+			r.outputFiles = append(r.outputFiles, outputFile)
+		case formatSearchablePDF:
+			jpegBytes, err := encodeJPEGBytes(img, cfg.Quality)
+			if err != nil {
+				r.err = fmt.Errorf("failed to encode page %d for searchable PDF: %w", pageNum, err)
+				return r
+			}
+			r.searchable = &searchablePageData{
+				widthPx:  img.Bounds().Dx(),
+				heightPx: img.Bounds().Dy(),
+				dpi:      cfg.DPI,
+				jpeg:     jpegBytes,
+				words:    words,
+			}
+		case formatWebP:
+			outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_page_%03d.webp", prefix, pageNum))
+			if err := saveWebP(Fit(img, cfg.MaxWidth, cfg.MaxHeight), outputFile); err != nil {
+				r.err = fmt.Errorf("failed to save page %d: %w", pageNum, err)
+				return r
+			}
+			r.outputFiles = append(r.outputFiles, outputFile)
+		case formatTIFF:
+			thumb := Fit(img, cfg.MaxWidth, cfg.MaxHeight)
+			if cfg.MultiPage {
+				r.tiffImage = thumb
+			} else {
+				outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_page_%03d.tiff", prefix, pageNum))
+				if err := saveTIFF(thumb, outputFile); err != nil {
+					r.err = fmt.Errorf("failed to save page %d: %w", pageNum, err)
+					return r
+				}
+				r.outputFiles = append(r.outputFiles, outputFile)
+			}
+		case formatJBIG2:
+			outputFile := filepath.Join(outputDir, fmt.Sprintf("%s_page_%03d.jb2", prefix, pageNum))
+			binarized := otsuBinarize(Fit(img, cfg.MaxWidth, cfg.MaxHeight))
+			data, err := encodeJBIG2(binarized)
+			if err != nil {
+				r.err = fmt.Errorf("failed to encode page %d as JBIG2: %w", pageNum, err)
+				return r
+			}
+			if err := os.WriteFile(outputFile, data, 0644); err != nil {
+				r.err = fmt.Errorf("failed to save page %d: %w", pageNum, err)
+				return r
+			}
+			r.outputFiles = append(r.outputFiles, outputFile)
+		}
+	}
+
+	return r
+}
+
+// drainWithError reports a single fatal error (e.g. the worker couldn't get
+// a PDFium instance) and stops pulling jobs. renderPages cancels the other
+// workers once it receives that error.
+func (c *Converter) drainWithError(ctx context.Context, jobs <-chan int, results chan<- pageJobResult, err error) {
+	select {
+	case results <- pageJobResult{err: err}:
+	case <-ctx.Done():
+	}
 }
 
 // parsePageRange parses a page range string and returns a slice of page numbers
@@ -270,14 +644,21 @@ func saveImage(img image.Image, path string, format string, quality int) error {
 	}
 	defer file.Close()
 
+	return EncodeImage(file, img, format, quality)
+}
+
+// EncodeImage encodes img to w in the given format ("png" or "jpeg"/"jpg").
+// It is exported so callers that don't want a file on disk, such as
+// ConvertStream or the CLI's stdout mode, can reuse the same encoding logic.
+func EncodeImage(w io.Writer, img image.Image, format string, quality int) error {
 	switch strings.ToLower(format) {
 	case "jpeg", "jpg":
 		opts := &jpeg.Options{Quality: quality}
-		if err := jpeg.Encode(file, img, opts); err != nil {
+		if err := jpeg.Encode(w, img, opts); err != nil {
 			return fmt.Errorf("failed to encode JPEG: %w", err)
 		}
 	case "png":
-		if err := png.Encode(file, img); err != nil {
+		if err := png.Encode(w, img); err != nil {
 			return fmt.Errorf("failed to encode PNG: %w", err)
 		}
 	default:
@@ -287,15 +668,56 @@ func saveImage(img image.Image, path string, format string, quality int) error {
 	return nil
 }
 
-// ValidateFormat checks if the format is valid
+// Recognized members of a Config.Format comma-list.
+const (
+	formatTXT           = "txt"
+	formatHOCR          = "hocr"
+	formatSearchablePDF = "searchable-pdf"
+	formatWebP          = "webp"
+	formatTIFF          = "tiff"
+	formatJBIG2         = "jbig2"
+)
+
+// ValidateFormat checks that format is a non-empty comma-separated list of
+// supported output formats.
 func ValidateFormat(format string) error {
-	format = strings.ToLower(format)
-	if format != "png" && format != "jpeg" && format != "jpg" {
-		return fmt.Errorf("invalid format: %s (must be png or jpeg)", format)
+	formats := parseFormats(format)
+	if len(formats) == 0 {
+		return fmt.Errorf("invalid format: %s", format)
+	}
+	for _, f := range formats {
+		switch f {
+		case "png", "jpeg", "jpg", formatTXT, formatHOCR, formatSearchablePDF, formatWebP, formatTIFF, formatJBIG2:
+		default:
+			return fmt.Errorf("invalid format: %s (must be one of png, jpeg, txt, hocr, searchable-pdf, webp, tiff, jbig2)", f)
+		}
 	}
 	return nil
 }
 
+// parseFormats splits a comma-separated Config.Format string into its
+// lowercased, trimmed members.
+func parseFormats(format string) []string {
+	var formats []string
+	for _, f := range strings.Split(format, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f != "" {
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
+
+// containsFormat reports whether formats contains format.
+func containsFormat(formats []string, format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidatePageRange validates a page range string format (without knowing total pages)
 func ValidatePageRange(rangeStr string) error {
 	rangeStr = strings.TrimSpace(strings.ToLower(rangeStr))
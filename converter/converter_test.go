@@ -0,0 +1,156 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// blankMultiPagePDF writes a minimal, valid n-page PDF (empty pages, no
+// content stream) to path. It is deliberately hand-rolled in the same style
+// as the searchable-pdf writer rather than pulling in a PDF library, since
+// tests in this package only need "n renderable pages".
+func blankMultiPagePDF(t *testing.T, path string, n int) {
+	t.Helper()
+
+	w := newPDFWriter()
+	catalogNum := w.alloc()
+	pagesNum := w.alloc()
+
+	kids := ""
+	for i := 0; i < n; i++ {
+		pageNum := w.alloc()
+		w.add(pageNum, []byte(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 200 200] /Resources << >> >>", pagesNum)))
+		kids += fmt.Sprintf("%d 0 R ", pageNum)
+	}
+	w.add(pagesNum, []byte(fmt.Sprintf("<< /Type /Pages /Kids [ %s] /Count %d >>", kids, n)))
+	w.add(catalogNum, []byte(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesNum)))
+
+	if err := os.WriteFile(path, w.bytes(catalogNum), 0644); err != nil {
+		t.Fatalf("failed to write test PDF: %v", err)
+	}
+}
+
+// TestConvertPreservesPageOrderWithWorkers guards the worker pool's stable
+// ordering guarantee: with WorkerCount > 1, pages can finish rendering out
+// of order, but renderPages re-assembles OutputFiles by page index, so the
+// result must always come back in page order regardless of completion
+// order.
+func TestConvertPreservesPageOrderWithWorkers(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "blank.pdf")
+	const pageCount = 8
+	blankMultiPagePDF(t, inputFile, pageCount)
+
+	conv, err := New(4)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer conv.Close()
+
+	outputDir := filepath.Join(dir, "out")
+	result, err := conv.Convert(context.Background(), Config{
+		InputFile: inputFile,
+		OutputDir: outputDir,
+		Format:    "png",
+		Quality:   80,
+		DPI:       72,
+		Pages:     "all",
+		Prefix:    "p",
+	})
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+	if len(result.OutputFiles) != pageCount {
+		t.Fatalf("expected %d output files, got %d: %v", pageCount, len(result.OutputFiles), result.OutputFiles)
+	}
+
+	for i, f := range result.OutputFiles {
+		want := fmt.Sprintf("p_page_%03d.png", i+1)
+		if filepath.Base(f) != want {
+			t.Fatalf("output file %d out of order: got %s, want %s (full list: %v)", i, filepath.Base(f), want, result.OutputFiles)
+		}
+	}
+}
+
+// TestConvertHOCROnlyDoesNotPanic guards against renderOnePage dereferencing
+// a nil image: hOCR needs the rendered page's pixel dimensions for its
+// page/word bboxes, so requesting "-f hocr" without also requesting an
+// image format must still render (and must not panic).
+func TestConvertHOCROnlyDoesNotPanic(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "blank.pdf")
+	blankMultiPagePDF(t, inputFile, 1)
+
+	conv, err := New(1)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer conv.Close()
+
+	outputDir := filepath.Join(dir, "out")
+	result, err := conv.Convert(context.Background(), Config{
+		InputFile: inputFile,
+		OutputDir: outputDir,
+		Format:    "txt,hocr",
+		DPI:       72,
+		Pages:     "all",
+		Prefix:    "p",
+	})
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected success, got error: %s", result.Error)
+	}
+
+	foundHOCR := false
+	for _, f := range result.OutputFiles {
+		if filepath.Ext(f) == ".hocr" {
+			foundHOCR = true
+		}
+	}
+	if !foundHOCR {
+		t.Fatalf("expected a .hocr output file, got: %v", result.OutputFiles)
+	}
+}
+
+// TestResolvedPageCount guards the "-o -" stdout path's up-front selection
+// check: it must resolve a multi-page selection to its true page count
+// before anything is streamed, not just after the second page arrives.
+func TestResolvedPageCount(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "blank.pdf")
+	blankMultiPagePDF(t, inputFile, 3)
+	pdfData, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	conv, err := New(1)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer conv.Close()
+
+	n, err := conv.ResolvedPageCount(pdfData, "1-2")
+	if err != nil {
+		t.Fatalf("ResolvedPageCount: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 resolved pages for \"1-2\", got %d", n)
+	}
+
+	n, err = conv.ResolvedPageCount(pdfData, "2")
+	if err != nil {
+		t.Fatalf("ResolvedPageCount: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 resolved page for \"2\", got %d", n)
+	}
+}
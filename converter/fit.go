@@ -0,0 +1,78 @@
+package converter
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Fit resizes img to fit within maxWidth x maxHeight, preserving aspect
+// ratio, using a high-quality Catmull-Rom resampler. A zero bound is
+// unbounded; if img already fits both bounds it is returned unchanged.
+func Fit(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 || (maxWidth <= 0 && maxHeight <= 0) {
+		return img
+	}
+
+	targetW, targetH := w, h
+	if maxWidth > 0 && targetW > maxWidth {
+		targetH = targetH * maxWidth / targetW
+		targetW = maxWidth
+	}
+	if maxHeight > 0 && targetH > maxHeight {
+		targetW = targetW * maxHeight / targetH
+		targetH = maxHeight
+	}
+	if targetW == w && targetH == h {
+		return img
+	}
+	if targetW < 1 {
+		targetW = 1
+	}
+	if targetH < 1 {
+		targetH = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, targetW, targetH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// minJPEGQuality is the floor fitJPEGToBudget will drop to when chasing a
+// byte budget; below this JPEG artifacting gets too aggressive to be useful.
+const minJPEGQuality = 20
+
+// fitJPEGToBudget re-encodes img as JPEG, binary-searching quality between
+// minJPEGQuality and maxQuality for the highest quality whose encoded size
+// is at or under maxBytes. If even minJPEGQuality doesn't fit, it returns
+// that smallest encoding anyway rather than failing the conversion.
+func fitJPEGToBudget(img image.Image, maxQuality, maxBytes int) ([]byte, error) {
+	lo := minJPEGQuality
+	hi := maxQuality
+	if hi < lo {
+		hi = lo
+	}
+
+	best, err := encodeJPEGBytes(img, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		data, err := encodeJPEGBytes(img, mid)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) <= maxBytes {
+			best = data
+			lo = mid + 1
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	return best, nil
+}
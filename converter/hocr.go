@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// buildHOCR renders a page's extracted words as an hOCR XML document. Word
+// coordinates are pdfium's PixelPosition (bottom-left origin, Top > Bottom)
+// and get flipped to hOCR's top-left-origin image space here, so downstream
+// OCR-review tools can overlay the boxes directly on the rendered image.
+func buildHOCR(pageNum, width, height int, words []textWord) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">` + "\n")
+	b.WriteString(`<html xmlns="http://www.w3.org/1999/xhtml"><head><meta http-equiv="Content-Type" content="text/html;charset=utf-8"/><meta name="ocr-system" content="pdf2img"/></head><body>` + "\n")
+	fmt.Fprintf(&b, `<div class="ocr_page" id="page_%d" title="bbox 0 0 %d %d">`+"\n", pageNum, width, height)
+	for i, w := range words {
+		y0 := height - int(w.Top)
+		y1 := height - int(w.Bottom)
+		fmt.Fprintf(&b, `<span class="ocrx_word" id="word_%d_%d" title="bbox %d %d %d %d">%s</span>`+"\n",
+			pageNum, i+1, int(w.Left), y0, int(w.Right), y1, html.EscapeString(w.Text))
+	}
+	b.WriteString("</div></body></html>\n")
+	return b.String()
+}
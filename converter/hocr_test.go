@@ -0,0 +1,24 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestBuildHOCRFlipsToImageSpace guards against emitting pdfium's
+// bottom-left-origin PixelPosition boxes unflipped: hOCR boxes must be in
+// top-left-origin image space with y0 < y1 so they overlay the rendered
+// page image.
+func TestBuildHOCRFlipsToImageSpace(t *testing.T) {
+	const height = 1100
+	words := []textWord{
+		{Text: "Hello", Left: 103, Top: 996, Right: 343, Bottom: 965},
+	}
+
+	out := buildHOCR(1, 850, height, words)
+
+	want := `title="bbox 103 104 343 135"`
+	if !strings.Contains(out, want) {
+		t.Fatalf("expected flipped, top-left-origin bbox %q in output:\n%s", want, out)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !jbig2
+
+package converter
+
+import (
+	"fmt"
+	"image"
+)
+
+// encodeJBIG2 requires linking against jbig2enc, which is only pulled in
+// when built with "-tags jbig2" (see jbig2_cgo.go). The default build keeps
+// pdf2img free of cgo and external C dependencies.
+func encodeJBIG2(img image.Image) ([]byte, error) {
+	return nil, fmt.Errorf("jbig2 output requires building pdf2img with -tags jbig2 and jbig2enc installed")
+}
@@ -0,0 +1,57 @@
+//go:build jbig2
+
+package converter
+
+/*
+#cgo LDFLAGS: -ljbig2enc -lleptonica
+#include <stdlib.h>
+#include <jbig2enc.h>
+#include <leptonica/allheaders.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"image"
+	"unsafe"
+)
+
+// encodeJBIG2 hands a 1-bit-per-pixel Leptonica PIX over to jbig2enc and
+// returns the generic-region-coded bitstream. img must already be
+// black-and-white (see otsuBinarize); jbig2enc does the rest.
+func encodeJBIG2(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	pix := C.pixCreate(C.l_int32(width), C.l_int32(height), 1)
+	if pix == nil {
+		return nil, fmt.Errorf("failed to allocate PIX for jbig2 encoding")
+	}
+	defer C.pixDestroy(&pix)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, _, _, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			if r == 0 {
+				C.pixSetPixel(pix, C.l_int32(x), C.l_int32(y), 1)
+			}
+		}
+	}
+
+	ctx := C.jbig2_init(C.double(0), C.double(0))
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to initialize jbig2enc context")
+	}
+	defer C.jbig2_destroy(ctx)
+
+	C.jbig2_add_page(ctx, pix)
+
+	var length C.int
+	data := C.jbig2_pages_complete(ctx, &length)
+	if data == nil {
+		return nil, fmt.Errorf("jbig2enc returned no data")
+	}
+	defer C.free(unsafe.Pointer(data))
+
+	return C.GoBytes(unsafe.Pointer(data), length), nil
+}
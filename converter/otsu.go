@@ -0,0 +1,72 @@
+package converter
+
+import (
+	"image"
+	"image/color"
+)
+
+// otsuBinarize converts img to black-and-white using Otsu's method, which
+// picks the threshold that minimizes intra-class variance between the
+// foreground and background pixel intensities. This is the standard
+// preprocessing step for JBIG2, which only encodes 1-bit images.
+func otsuBinarize(img image.Image) image.Image {
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	var histogram [256]int
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+			gray.SetGray(x, y, c)
+			histogram[c.Y]++
+		}
+	}
+
+	threshold := otsuThreshold(histogram, bounds.Dx()*bounds.Dy())
+
+	out := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if gray.GrayAt(x, y).Y > threshold {
+				out.SetGray(x, y, color.Gray{Y: 255})
+			} else {
+				out.SetGray(x, y, color.Gray{Y: 0})
+			}
+		}
+	}
+	return out
+}
+
+// otsuThreshold finds the gray level that maximizes the between-class
+// variance of histogram, a 256-bucket intensity histogram over total pixels.
+func otsuThreshold(histogram [256]int, total int) uint8 {
+	var sumAll float64
+	for i, count := range histogram {
+		sumAll += float64(i * count)
+	}
+
+	var sumBackground, weightBackground float64
+	var bestThreshold uint8
+	var bestVariance float64
+
+	for t := 0; t < 256; t++ {
+		weightBackground += float64(histogram[t])
+		if weightBackground == 0 {
+			continue
+		}
+		weightForeground := float64(total) - weightBackground
+		if weightForeground == 0 {
+			break
+		}
+
+		sumBackground += float64(t * histogram[t])
+		meanBackground := sumBackground / weightBackground
+		meanForeground := (sumAll - sumBackground) / weightForeground
+
+		variance := weightBackground * weightForeground * (meanBackground - meanForeground) * (meanBackground - meanForeground)
+		if variance > bestVariance {
+			bestVariance = variance
+			bestThreshold = uint8(t)
+		}
+	}
+	return bestThreshold
+}
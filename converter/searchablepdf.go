@@ -0,0 +1,244 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// searchablePageData is what a rendered page contributes to a combined
+// searchable PDF: the page image, already JPEG-encoded, plus the word boxes
+// of its invisible text layer. Both are in pixel space at the render DPI.
+type searchablePageData struct {
+	widthPx  int
+	heightPx int
+	dpi      int
+	jpeg     []byte
+	words    []textWord
+}
+
+// encodeJPEGBytes is a small wrapper around image/jpeg for callers, like the
+// searchable-pdf path, that need the encoded bytes rather than a file.
+func encodeJPEGBytes(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSearchablePDF assembles every rendered page into a single PDF and
+// writes it to "<prefix>.searchable.pdf" in outputDir.
+func (c *Converter) writeSearchablePDF(pages []*searchablePageData, outputDir, prefix string) (string, error) {
+	pdfBytes, err := buildSearchablePDF(pages)
+	if err != nil {
+		return "", fmt.Errorf("failed to build searchable PDF: %w", err)
+	}
+
+	outputFile := filepath.Join(outputDir, prefix+".searchable.pdf")
+	if err := os.WriteFile(outputFile, pdfBytes, 0644); err != nil {
+		return "", fmt.Errorf("failed to write searchable PDF: %w", err)
+	}
+	return outputFile, nil
+}
+
+// buildSearchablePDF mirrors the hocr+image->PDF flow used by OCR review
+// tools: each page is the rendered image with its extracted words stamped
+// beneath it in invisible (render mode 3) text, horizontally scaled to match
+// each word's box so text selection lines up with what's on the page. This
+// is a minimal, hand-rolled writer rather than a pulled-in PDF library,
+// since all that's needed is "one image + one text layer per page".
+func buildSearchablePDF(pages []*searchablePageData) ([]byte, error) {
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages to assemble")
+	}
+
+	w := newPDFWriter()
+	catalogNum := w.alloc()
+	pagesNum := w.alloc()
+	fontNum := w.alloc()
+	w.add(fontNum, []byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"))
+
+	pageNums := make([]int, 0, len(pages))
+	for _, p := range pages {
+		if p == nil {
+			return nil, fmt.Errorf("missing rendered data for a selected page")
+		}
+
+		pageNum, err := addSearchablePage(w, p, pagesNum, fontNum)
+		if err != nil {
+			return nil, err
+		}
+		pageNums = append(pageNums, pageNum)
+	}
+
+	var kids bytes.Buffer
+	for _, n := range pageNums {
+		fmt.Fprintf(&kids, "%d 0 R ", n)
+	}
+	w.add(pagesNum, []byte(fmt.Sprintf("<< /Type /Pages /Kids [ %s] /Count %d >>", kids.String(), len(pageNums))))
+	w.add(catalogNum, []byte(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesNum)))
+
+	return w.bytes(catalogNum), nil
+}
+
+// addSearchablePage writes one page's image XObject, content stream and
+// page object, returning the page object's number.
+func addSearchablePage(w *pdfWriter, p *searchablePageData, pagesNum, fontNum int) (int, error) {
+	scale := 72.0 / float64(p.dpi)
+	pageW := float64(p.widthPx) * scale
+	pageH := float64(p.heightPx) * scale
+
+	imgNum := w.alloc()
+	var img bytes.Buffer
+	fmt.Fprintf(&img, "<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n", p.widthPx, p.heightPx, len(p.jpeg))
+	img.Write(p.jpeg)
+	img.WriteString("\nendstream")
+	w.add(imgNum, img.Bytes())
+
+	content := buildPageContentStream(p, pageW, pageH, scale)
+	contentNum := w.alloc()
+	w.add(contentNum, []byte(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)))
+
+	pageNum := w.alloc()
+	pageBody := fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /XObject << /Im0 %d 0 R >> /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+		pagesNum, pageW, pageH, imgNum, fontNum, contentNum,
+	)
+	w.add(pageNum, []byte(pageBody))
+
+	return pageNum, nil
+}
+
+// buildPageContentStream draws the page image full-bleed, then stamps each
+// word as invisible text positioned from its pixel bounding box.
+func buildPageContentStream(p *searchablePageData, pageW, pageH, scale float64) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "q %.2f 0 0 %.2f 0 0 cm /Im0 Do Q\n", pageW, pageH)
+
+	for _, word := range p.words {
+		// word's coordinates are pdfium's PixelPosition, which is already
+		// bottom-left origin like PDF user space (Top > Bottom), so the
+		// baseline and height need no Y-flip here.
+		xPt := word.Left * scale
+		yPt := word.Bottom * scale
+		boxW := (word.Right - word.Left) * scale
+		boxH := (word.Top - word.Bottom) * scale
+		if boxW <= 0 || boxH <= 0 {
+			continue
+		}
+
+		fontSize := boxH * 0.9
+		hScale := textHorizontalScale(word.Text, fontSize, boxW)
+
+		fmt.Fprintf(&b, "BT /F1 %.2f Tf 3 Tr %.2f Tz 1 0 0 1 %.2f %.2f Tm (%s) Tj ET\n",
+			fontSize, hScale, xPt, yPt, escapePDFString(word.Text))
+	}
+
+	return b.String()
+}
+
+// textHorizontalScale returns the Tz horizontal-scaling percentage needed to
+// stretch text's rendered width (at fontSize, Helvetica's average glyph
+// width) to fill targetWidth, so the invisible text lines up with its word
+// box for selection and copy/paste.
+func textHorizontalScale(text string, fontSize, targetWidth float64) float64 {
+	const avgGlyphWidthFactor = 0.5 // rough average advance width for Helvetica, in units of font size
+	naturalWidth := float64(len([]rune(text))) * fontSize * avgGlyphWidthFactor
+	if naturalWidth <= 0 {
+		return 100
+	}
+	scale := targetWidth / naturalWidth * 100
+	if scale < 1 {
+		scale = 1
+	}
+	if scale > 1000 {
+		scale = 1000
+	}
+	return scale
+}
+
+// escapePDFString escapes a word for use inside a PDF literal string, ()
+// falling back to '?' for anything outside WinAnsiEncoding's ASCII range.
+func escapePDFString(s string) string {
+	var b bytes.Buffer
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// pdfObject is a single, already-serialized indirect object body.
+type pdfObject struct {
+	num  int
+	body []byte
+}
+
+// pdfWriter accumulates indirect objects and serializes them into a minimal
+// single-revision PDF file with a plain (non-compressed) xref table.
+type pdfWriter struct {
+	objects []pdfObject
+	nextNum int
+}
+
+func newPDFWriter() *pdfWriter {
+	return &pdfWriter{nextNum: 1}
+}
+
+func (w *pdfWriter) alloc() int {
+	n := w.nextNum
+	w.nextNum++
+	return n
+}
+
+func (w *pdfWriter) add(num int, body []byte) {
+	w.objects = append(w.objects, pdfObject{num: num, body: body})
+}
+
+// bytes serializes all added objects into a complete PDF file, with rootNum
+// as the /Root of the trailer.
+func (w *pdfWriter) bytes(rootNum int) []byte {
+	sort.Slice(w.objects, func(i, j int) bool { return w.objects[i].num < w.objects[j].num })
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	offsets := make(map[int]int, len(w.objects))
+	maxNum := 0
+	for _, obj := range w.objects {
+		offsets[obj.num] = buf.Len()
+		if obj.num > maxNum {
+			maxNum = obj.num
+		}
+		fmt.Fprintf(&buf, "%d 0 obj\n", obj.num)
+		buf.Write(obj.body)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xrefStart := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", maxNum+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for i := 1; i <= maxNum; i++ {
+		off, ok := offsets[i]
+		if !ok {
+			buf.WriteString("0000000000 65535 f \n")
+			continue
+		}
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF", maxNum+1, rootNum, xrefStart)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// solidImage returns a tiny solid-color image for tests that just need
+// something jpeg.Encode can accept.
+func solidImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	return img
+}
+
+// TestBuildPageContentStreamKeepsWords guards against the Top/Bottom sign
+// flip: pdfium's PixelPosition is already bottom-left origin (Top > Bottom),
+// so boxH must be computed as Top-Bottom, not Bottom-Top, or every word's
+// box looks zero/negative height and gets dropped from the text layer.
+func TestBuildPageContentStreamKeepsWords(t *testing.T) {
+	p := &searchablePageData{
+		widthPx:  850,
+		heightPx: 1100,
+		dpi:      72,
+		words: []textWord{
+			{Text: "Hello", Left: 100, Top: 996, Right: 200, Bottom: 965},
+		},
+	}
+	scale := 72.0 / float64(p.dpi)
+	pageH := float64(p.heightPx) * scale
+
+	content := buildPageContentStream(p, float64(p.widthPx)*scale, pageH, scale)
+
+	if !strings.Contains(content, "(Hello) Tj") {
+		t.Fatalf("expected word to be stamped as invisible text, got:\n%s", content)
+	}
+	if strings.Contains(content, "1 0 0 1 100.00 0.00 Tm") {
+		t.Fatalf("word placed at y=0, box was dropped or mis-flipped:\n%s", content)
+	}
+}
+
+// TestBuildSearchablePDFTextRoundTrips builds a full single-page searchable
+// PDF and checks the invisible text layer the page would render actually
+// made it into the content stream, so re-extracting text from the output
+// would find the word instead of an image with no text layer.
+func TestBuildSearchablePDFTextRoundTrips(t *testing.T) {
+	img, err := encodeJPEGBytes(solidImage(10, 10), 80)
+	if err != nil {
+		t.Fatalf("encodeJPEGBytes: %v", err)
+	}
+
+	page := &searchablePageData{
+		widthPx:  10,
+		heightPx: 10,
+		dpi:      72,
+		jpeg:     img,
+		words: []textWord{
+			{Text: "hi", Left: 1, Top: 9, Right: 5, Bottom: 2},
+		},
+	}
+
+	pdfBytes, err := buildSearchablePDF([]*searchablePageData{page})
+	if err != nil {
+		t.Fatalf("buildSearchablePDF: %v", err)
+	}
+
+	if !bytes.Contains(pdfBytes, []byte("(hi) Tj")) {
+		t.Fatalf("searchable PDF has no text layer for the word; got:\n%s", pdfBytes)
+	}
+}
@@ -0,0 +1,61 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/klippa-app/go-pdfium"
+	"github.com/klippa-app/go-pdfium/requests"
+)
+
+// textWord is a single word of extracted page text together with its
+// bounding box in pixel space, at the DPI the page was rendered at.
+type textWord struct {
+	Text                     string
+	Left, Top, Right, Bottom float64
+}
+
+// pageText holds everything extracted from a page's text layer.
+type pageText struct {
+	Text  string
+	Words []textWord
+}
+
+// extractPageText pulls the plain text and per-word pixel bounding boxes for
+// a single page, using go-pdfium's structured text API so the boxes line up
+// exactly with a page rendered via RenderPageInDPI at the same dpi.
+func extractPageText(instance pdfium.Pdfium, page requests.Page, dpi int) (*pageText, error) {
+	textResp, err := instance.GetPageText(&requests.GetPageText{Page: page})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page text: %w", err)
+	}
+
+	structuredResp, err := instance.GetPageTextStructured(&requests.GetPageTextStructured{
+		Page: page,
+		Mode: requests.GetPageTextStructuredModeRects,
+		PixelPositions: requests.GetPageTextStructuredPixelPositions{
+			Document:  page.ByIndex.Document,
+			Calculate: true,
+			DPI:       dpi,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get structured page text: %w", err)
+	}
+
+	words := make([]textWord, 0, len(structuredResp.Rects))
+	for _, rect := range structuredResp.Rects {
+		if rect.PixelPosition == nil || strings.TrimSpace(rect.Text) == "" {
+			continue
+		}
+		words = append(words, textWord{
+			Text:   rect.Text,
+			Left:   rect.PixelPosition.Left,
+			Top:    rect.PixelPosition.Top,
+			Right:  rect.PixelPosition.Right,
+			Bottom: rect.PixelPosition.Bottom,
+		})
+	}
+
+	return &pageText{Text: textResp.Text, Words: words}, nil
+}
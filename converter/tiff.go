@@ -0,0 +1,188 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/image/tiff"
+)
+
+// saveTIFF writes a single-page baseline TIFF, delegating to x/image/tiff.
+func saveTIFF(img image.Image, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := tiff.Encode(file, img, nil); err != nil {
+		return fmt.Errorf("failed to encode TIFF: %w", err)
+	}
+	return nil
+}
+
+// writeMultipageTIFFFile assembles every page into one multi-page TIFF and
+// writes it to "<prefix>.tiff" in outputDir.
+func writeMultipageTIFFFile(pages []image.Image, dpi int, outputDir, prefix string) (string, error) {
+	for _, p := range pages {
+		if p == nil {
+			return "", fmt.Errorf("missing rendered data for a selected page")
+		}
+	}
+
+	data, err := writeMultipageTIFF(pages, dpi)
+	if err != nil {
+		return "", fmt.Errorf("failed to build multi-page TIFF: %w", err)
+	}
+
+	outputFile := filepath.Join(outputDir, prefix+".tiff")
+	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write multi-page TIFF: %w", err)
+	}
+	return outputFile, nil
+}
+
+// TIFF baseline tag IDs used by writeMultipageTIFF. See TIFF 6.0 Section 8.
+const (
+	tagImageWidth                = 256
+	tagImageLength               = 257
+	tagBitsPerSample             = 258
+	tagCompression               = 259
+	tagPhotometricInterpretation = 262
+	tagStripOffsets              = 273
+	tagSamplesPerPixel           = 277
+	tagRowsPerStrip              = 278
+	tagStripByteCounts           = 279
+	tagXResolution               = 282
+	tagYResolution               = 283
+	tagResolutionUnit            = 296
+)
+
+const tiffTagsPerIFD = 12
+
+// writeMultipageTIFF hand-assembles a baseline, uncompressed TIFF containing
+// one IFD per image, chained via each IFD's "next IFD offset" field, so a
+// reader sees a single multi-page document. x/image/tiff only encodes one
+// image per file, so a combined file needs its own writer.
+func writeMultipageTIFF(images []image.Image, dpi int) ([]byte, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no pages to assemble")
+	}
+
+	order := binary.LittleEndian
+
+	// Shared tag values too large for an IFD entry's 4-byte inline slot:
+	// BitsPerSample (3 x SHORT) and the X/Y resolution rationals. Every page
+	// points at the same copy since the values never change per page.
+	const sharedDataOffset = 8 // right after the 8-byte header
+	var shared bytes.Buffer
+	binary.Write(&shared, order, []uint16{8, 8, 8}) // BitsPerSample: 8 bits/channel, RGB
+	xResOffset := sharedDataOffset + shared.Len()
+	binary.Write(&shared, order, []uint32{uint32(dpi), 1}) // XResolution
+	yResOffset := sharedDataOffset + shared.Len()
+	binary.Write(&shared, order, []uint32{uint32(dpi), 1}) // YResolution
+	bitsPerSampleOffset := sharedDataOffset
+
+	stripOffset := sharedDataOffset + shared.Len()
+	type pageInfo struct {
+		pix            []byte
+		width, height  int
+		stripOffset    int
+		stripByteCount int
+	}
+	pages := make([]pageInfo, len(images))
+	for i, img := range images {
+		pix := toRGB(img)
+		pages[i] = pageInfo{
+			pix:            pix,
+			width:          img.Bounds().Dx(),
+			height:         img.Bounds().Dy(),
+			stripOffset:    stripOffset,
+			stripByteCount: len(pix),
+		}
+		stripOffset += len(pix)
+	}
+
+	ifdOffset := stripOffset
+	ifdSize := 2 + tiffTagsPerIFD*12 + 4 // entry count + entries + next-IFD pointer
+	ifdOffsets := make([]int, len(pages))
+	for i := range pages {
+		ifdOffsets[i] = ifdOffset + i*ifdSize
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("II")
+	binary.Write(&buf, order, uint16(42))
+	binary.Write(&buf, order, uint32(ifdOffsets[0]))
+	buf.Write(shared.Bytes())
+	for _, p := range pages {
+		buf.Write(p.pix)
+	}
+
+	for i, p := range pages {
+		next := uint32(0)
+		if i < len(pages)-1 {
+			next = uint32(ifdOffsets[i+1])
+		}
+		writeIFD(&buf, order, p.width, p.height, bitsPerSampleOffset, xResOffset, yResOffset, p.stripOffset, p.stripByteCount, next)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeIFD appends one baseline RGB image's IFD (12 tags, ascending by ID as
+// required by the spec) to buf.
+func writeIFD(buf *bytes.Buffer, order binary.ByteOrder, width, height, bitsPerSampleOffset, xResOffset, yResOffset, stripOffset, stripByteCount int, nextIFD uint32) {
+	type entry struct {
+		tag, typ uint16
+		count    uint32
+		value    uint32
+	}
+	const (
+		typeShort    = 3
+		typeLong     = 4
+		typeRational = 5
+	)
+
+	entries := []entry{
+		{tagImageWidth, typeLong, 1, uint32(width)},
+		{tagImageLength, typeLong, 1, uint32(height)},
+		{tagBitsPerSample, typeShort, 3, uint32(bitsPerSampleOffset)},
+		{tagCompression, typeShort, 1, 1},
+		{tagPhotometricInterpretation, typeShort, 1, 2},
+		{tagStripOffsets, typeLong, 1, uint32(stripOffset)},
+		{tagSamplesPerPixel, typeShort, 1, 3},
+		{tagRowsPerStrip, typeLong, 1, uint32(height)},
+		{tagStripByteCounts, typeLong, 1, uint32(stripByteCount)},
+		{tagXResolution, typeRational, 1, uint32(xResOffset)},
+		{tagYResolution, typeRational, 1, uint32(yResOffset)},
+		{tagResolutionUnit, typeShort, 1, 2},
+	}
+
+	binary.Write(buf, order, uint16(len(entries)))
+	for _, e := range entries {
+		binary.Write(buf, order, e.tag)
+		binary.Write(buf, order, e.typ)
+		binary.Write(buf, order, e.count)
+		binary.Write(buf, order, e.value)
+	}
+	binary.Write(buf, order, nextIFD)
+}
+
+// toRGB flattens img into tightly packed, row-major 8-bit RGB triples.
+func toRGB(img image.Image) []byte {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	out := make([]byte, 0, w*h*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			out = append(out, byte(r>>8), byte(g>>8), byte(b>>8))
+		}
+	}
+	return out
+}
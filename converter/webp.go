@@ -0,0 +1,24 @@
+package converter
+
+import (
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// saveWebP lossless-encodes img and writes it to path. Pure Go, no cgo:
+// nativewebp implements the VP8L bitstream directly.
+func saveWebP(img image.Image, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := nativewebp.Encode(file, img, nil); err != nil {
+		return fmt.Errorf("failed to encode WebP: %w", err)
+	}
+	return nil
+}
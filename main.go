@@ -1,13 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"image"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/potatoqualitee/aitools/tools/pdf2img/converter"
+	"github.com/potatoqualitee/pdf2img/converter"
+	"github.com/potatoqualitee/pdf2img/server"
 	"github.com/spf13/pflag"
 )
 
@@ -17,32 +23,49 @@ var (
 
 // Exit codes
 const (
-	ExitSuccess           = 0
-	ExitInvalidArgs       = 1
-	ExitInputNotFound     = 2
-	ExitInvalidPDF        = 3
-	ExitOutputDirError    = 4
-	ExitRenderFailed      = 5
-	ExitWriteFailed       = 6
-	ExitInitFailed        = 7
+	ExitSuccess        = 0
+	ExitInvalidArgs    = 1
+	ExitInputNotFound  = 2
+	ExitInvalidPDF     = 3
+	ExitOutputDirError = 4
+	ExitRenderFailed   = 5
+	ExitWriteFailed    = 6
+	ExitInitFailed     = 7
 )
 
 func main() {
+	// "pdf2img serve" runs a long-lived HTTP server instead of the usual
+	// one-shot conversion, so it gets its own flag set and entry point.
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+
 	// Define flags
 	output := pflag.StringP("output", "o", "", "Output directory (default: same as input file)")
-	format := pflag.StringP("format", "f", "png", "Output format: png or jpeg")
+	format := pflag.StringP("format", "f", "png", "Output format(s), comma-separated: png, jpeg, txt, hocr, searchable-pdf, webp, tiff, jbig2")
 	quality := pflag.IntP("quality", "q", 85, "JPEG quality (1-100)")
 	dpi := pflag.IntP("dpi", "d", 150, "Render resolution in DPI")
 	pages := pflag.StringP("pages", "p", "all", "Pages to convert: all, 1, 1-5, 1,3,5")
 	prefix := pflag.String("prefix", "", "Output filename prefix (default: input filename)")
 	jsonOutput := pflag.Bool("json", false, "Output results as JSON")
+	workers := pflag.IntP("workers", "w", 1, "Number of pages to render in parallel")
+	progress := pflag.Bool("progress", false, "Print a live progress bar while rendering")
+	maxWidth := pflag.Int("max-width", 0, "Downscale pages wider than this, preserving aspect ratio")
+	maxHeight := pflag.Int("max-height", 0, "Downscale pages taller than this, preserving aspect ratio")
+	maxBytes := pflag.Int("max-bytes", 0, "Lower JPEG quality until the file fits this many bytes")
+	thumb := pflag.Bool("thumb", false, "Shortcut for a 200x200 JPEG thumbnail (--max-width 200 --max-height 200 -f jpeg)")
+	multipage := pflag.Bool("multipage", false, "With --format tiff, combine every page into a single multi-page TIFF")
+	recursive := pflag.Bool("recursive", false, "With a directory input, walk it for .pdf files")
+	continueOnError := pflag.Bool("continue-on-error", false, "With multiple inputs, keep processing after a file fails")
 	showVersion := pflag.Bool("version", false, "Print version and exit")
 
 	pflag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: pdf2img <input.pdf> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: pdf2img <input.pdf>... [options]\n\n")
 		fmt.Fprintf(os.Stderr, "Converts PDF pages to images (PNG or JPEG).\n\n")
 		fmt.Fprintf(os.Stderr, "Arguments:\n")
-		fmt.Fprintf(os.Stderr, "  <input.pdf>    Path to input PDF file\n\n")
+		fmt.Fprintf(os.Stderr, "  <input.pdf>...    One or more PDF files, glob patterns, or (with\n")
+		fmt.Fprintf(os.Stderr, "                    --recursive) directories to walk for .pdf files\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		pflag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
@@ -50,30 +73,61 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf -f jpeg -q 90 -d 300\n")
 		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf -p 1-5 -o ./images\n")
 		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf --pages \"1,3,5-7\" --prefix output\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf --workers 4 --progress\n")
+		fmt.Fprintf(os.Stderr, "  cat document.pdf | pdf2img - -o out/\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf -p 1 -o - > page1.png\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf --format txt,png\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf --thumb\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf -f jpeg --max-bytes 51200\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img document.pdf -f tiff --multipage\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img *.pdf -o out/ --json\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img ./scans --recursive --continue-on-error -o out/\n")
+		fmt.Fprintf(os.Stderr, "  pdf2img serve --addr :8080\n")
 	}
 
 	pflag.Parse()
 
+	// --thumb is a shortcut that sets a 200x200 bound when neither
+	// --max-width nor --max-height is given, and switches the format to
+	// jpeg when it is png (the default, or png given explicitly).
+	if *thumb {
+		if *maxWidth == 0 && *maxHeight == 0 {
+			*maxWidth = 200
+			*maxHeight = 200
+		}
+		if *format == "png" {
+			*format = "jpeg"
+		}
+	}
+
 	// Handle version flag
 	if *showVersion {
 		fmt.Printf("pdf2img version %s\n", version)
 		os.Exit(ExitSuccess)
 	}
 
-	// Check for input file argument
+	// Check for input file arguments. A filename of "-", or no filename at
+	// all, reads a single PDF from stdin so pdf2img can sit in a shell
+	// pipeline. Anything else is resolved to one or more PDF files, which
+	// may include glob patterns and (with --recursive) directories.
 	args := pflag.Args()
-	if len(args) < 1 {
-		fmt.Fprintln(os.Stderr, "Error: input PDF file is required")
-		pflag.Usage()
-		os.Exit(ExitInvalidArgs)
+	inputFiles, readStdin, err := resolveInputFiles(args, *recursive)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitInputNotFound)
 	}
 
-	inputFile := args[0]
-
-	// Validate input file exists
-	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: input file not found: %s\n", inputFile)
-		os.Exit(ExitInputNotFound)
+	var inputFile string
+	if readStdin {
+		tmpFile, err := bufferStdinToFile()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to read PDF from stdin: %v\n", err)
+			os.Exit(ExitInputNotFound)
+		}
+		defer os.Remove(tmpFile)
+		inputFile = tmpFile
+	} else {
+		inputFile = inputFiles[0]
 	}
 
 	// Validate format
@@ -95,17 +149,50 @@ func main() {
 		os.Exit(ExitInvalidArgs)
 	}
 
+	// Validate workers
+	if *workers < 1 {
+		fmt.Fprintln(os.Stderr, "Error: workers must be at least 1")
+		os.Exit(ExitInvalidArgs)
+	}
+
+	// Validate thumbnail bounds
+	if *maxWidth < 0 || *maxHeight < 0 || *maxBytes < 0 {
+		fmt.Fprintln(os.Stderr, "Error: max-width, max-height, and max-bytes must not be negative")
+		os.Exit(ExitInvalidArgs)
+	}
+
 	// Validate page range format
 	if err := converter.ValidatePageRange(*pages); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(ExitInvalidArgs)
 	}
 
-	// Resolve input file to absolute path
-	absInputFile, err := filepath.Abs(inputFile)
+	batch := !readStdin && len(inputFiles) > 1
+
+	// Create converter. Batch mode reuses this single Converter, and the
+	// PDFium pool inside it, across every input file.
+	conv, err := converter.New(*workers)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to resolve input path: %v\n", err)
-		os.Exit(ExitInvalidArgs)
+		fmt.Fprintf(os.Stderr, "Error: failed to initialize converter: %v\n", err)
+		os.Exit(ExitInitFailed)
+	}
+	defer conv.Close()
+
+	// "-o -" streams a single rendered page straight to stdout instead of
+	// writing files, for piping into another tool. Not meaningful for a
+	// batch of files.
+	if *output == "-" {
+		if batch {
+			fmt.Fprintln(os.Stderr, "Error: -o - is only supported for a single input file")
+			os.Exit(ExitInvalidArgs)
+		}
+		absInputFile, err := filepath.Abs(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to resolve input path: %v\n", err)
+			os.Exit(ExitInvalidArgs)
+		}
+		convertToStdout(conv, absInputFile, *dpi, *pages, *format, *quality)
+		os.Exit(ExitSuccess)
 	}
 
 	// Resolve output directory
@@ -119,29 +206,42 @@ func main() {
 		outputDir = absOutputDir
 	}
 
-	// Create converter
-	conv, err := converter.New()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to initialize converter: %v\n", err)
-		os.Exit(ExitInitFailed)
-	}
-	defer conv.Close()
-
-	// Build config
-	cfg := converter.Config{
-		InputFile: absInputFile,
+	cfgTemplate := converter.Config{
 		OutputDir: outputDir,
 		Format:    *format,
 		Quality:   *quality,
 		DPI:       *dpi,
 		Pages:     *pages,
 		Prefix:    *prefix,
+		MaxWidth:  *maxWidth,
+		MaxHeight: *maxHeight,
+		MaxBytes:  *maxBytes,
+		MultiPage: *multipage,
 	}
 
-	// Perform conversion
-	result, err := conv.Convert(cfg)
+	if *progress && !batch {
+		cfgTemplate.ProgressFunc = func(done, total, page int) {
+			fmt.Fprintf(os.Stderr, "\rRendering page %d (%d/%d)", page, done, total)
+			if done == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		}
+	}
+
+	if batch {
+		os.Exit(runBatch(conv, inputFiles, cfgTemplate, *jsonOutput, *continueOnError))
+	}
+
+	// Single-file mode
+	absInputFile, err := filepath.Abs(inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to resolve input path: %v\n", err)
+		os.Exit(ExitInvalidArgs)
+	}
+	cfgTemplate.InputFile = absInputFile
+
+	result, err := conv.Convert(context.Background(), cfgTemplate)
 
-	// Output results
 	if *jsonOutput {
 		jsonBytes, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(jsonBytes))
@@ -156,22 +256,196 @@ func main() {
 		}
 	}
 
-	// Determine exit code
-	if err != nil {
-		if strings.Contains(err.Error(), "failed to open PDF") {
-			os.Exit(ExitInvalidPDF)
+	os.Exit(exitCodeFor(err))
+}
+
+// exitCodeFor maps a Converter.Convert error to the CLI's exit code scheme.
+func exitCodeFor(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+	if strings.Contains(err.Error(), "failed to open PDF") {
+		return ExitInvalidPDF
+	}
+	if strings.Contains(err.Error(), "output directory") {
+		return ExitOutputDirError
+	}
+	if strings.Contains(err.Error(), "render") || strings.Contains(err.Error(), "extract text") {
+		return ExitRenderFailed
+	}
+	if strings.Contains(err.Error(), "save") || strings.Contains(err.Error(), "encode") || strings.Contains(err.Error(), "write") {
+		return ExitWriteFailed
+	}
+	return ExitInvalidArgs
+}
+
+// resolveInputFiles expands args into a concrete list of PDF paths. A single
+// "-" (or no args at all) means read one PDF from stdin. Directories are
+// only walked when recursive is set; anything else that isn't a literal
+// path is tried as a glob pattern, for shells that don't expand wildcards
+// themselves.
+func resolveInputFiles(args []string, recursive bool) (files []string, useStdin bool, err error) {
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+		return nil, true, nil
+	}
+
+	for _, arg := range args {
+		if arg == "-" {
+			return nil, false, fmt.Errorf("stdin (\"-\") cannot be combined with other inputs")
 		}
-		if strings.Contains(err.Error(), "output directory") {
-			os.Exit(ExitOutputDirError)
+
+		info, statErr := os.Stat(arg)
+		switch {
+		case statErr == nil && info.IsDir():
+			if !recursive {
+				return nil, false, fmt.Errorf("%s is a directory (use --recursive to walk it)", arg)
+			}
+			walkErr := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".pdf") {
+					files = append(files, path)
+				}
+				return nil
+			})
+			if walkErr != nil {
+				return nil, false, fmt.Errorf("failed to walk %s: %w", arg, walkErr)
+			}
+		case statErr == nil:
+			files = append(files, arg)
+		default:
+			matches, globErr := filepath.Glob(arg)
+			if globErr != nil || len(matches) == 0 {
+				return nil, false, fmt.Errorf("input file not found: %s", arg)
+			}
+			files = append(files, matches...)
 		}
-		if strings.Contains(err.Error(), "render") {
-			os.Exit(ExitRenderFailed)
+	}
+
+	if len(files) == 0 {
+		return nil, false, fmt.Errorf("no input files matched")
+	}
+	return files, false, nil
+}
+
+// runBatch converts every file in inputFiles against a single Converter,
+// printing either a summary table or (with jsonOutput) one Result per line
+// as NDJSON so callers can stream-parse the output. It returns the process
+// exit code: the first failing file's code, or ExitSuccess if every file
+// converted.
+func runBatch(conv *converter.Converter, inputFiles []string, cfgTemplate converter.Config, jsonOutput, continueOnError bool) int {
+	exitCode := ExitSuccess
+
+	for _, inputFile := range inputFiles {
+		absInputFile, err := filepath.Abs(inputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to resolve input path %s: %v\n", inputFile, err)
+			return ExitInvalidArgs
+		}
+
+		cfg := cfgTemplate
+		cfg.InputFile = absInputFile
+
+		result, convErr := conv.Convert(context.Background(), cfg)
+
+		if jsonOutput {
+			jsonBytes, _ := json.Marshal(result)
+			fmt.Println(string(jsonBytes))
+		} else if result.Success {
+			fmt.Printf("%s: converted %d page(s)\n", filepath.Base(inputFile), result.PageCount)
+			for _, f := range result.OutputFiles {
+				fmt.Printf("  %s\n", f)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: Error: %s\n", filepath.Base(inputFile), result.Error)
 		}
-		if strings.Contains(err.Error(), "save") || strings.Contains(err.Error(), "encode") {
-			os.Exit(ExitWriteFailed)
+
+		if convErr != nil && exitCode == ExitSuccess {
+			exitCode = exitCodeFor(convErr)
 		}
-		os.Exit(ExitInvalidArgs)
+		if convErr != nil && !continueOnError {
+			break
+		}
+	}
+
+	return exitCode
+}
+
+// runServe parses "pdf2img serve" flags and runs the HTTP conversion server
+// until it exits or is killed.
+func runServe(args []string) {
+	fs := pflag.NewFlagSet("serve", pflag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	maxUploadBytes := fs.Int64("max-upload-bytes", 0, "Max accepted upload size in bytes (default 64 MiB)")
+	maxPages := fs.Int("max-pages", 0, "Max pages per document (default 200)")
+	timeout := fs.Duration("timeout", 0, "Max time allowed per conversion request (default 60s)")
+	fs.Parse(args)
+
+	srv, err := server.New(server.Config{
+		Addr:           *addr,
+		MaxUploadBytes: *maxUploadBytes,
+		MaxPages:       *maxPages,
+		RequestTimeout: *timeout,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to start server: %v\n", err)
+		os.Exit(ExitInitFailed)
+	}
+	defer srv.Close()
+
+	fmt.Fprintf(os.Stderr, "pdf2img serve: listening on %s\n", *addr)
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: server stopped: %v\n", err)
+		os.Exit(ExitInitFailed)
+	}
+}
+
+// bufferStdinToFile copies stdin into a temp file so the rest of the CLI can
+// keep treating the input as a regular path on disk.
+func bufferStdinToFile() (string, error) {
+	tmpFile, err := os.CreateTemp("", "pdf2img-stdin-*.pdf")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer tmpFile.Close()
+
+	if _, err := io.Copy(tmpFile, os.Stdin); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", fmt.Errorf("failed to buffer stdin: %w", err)
 	}
 
-	os.Exit(ExitSuccess)
+	return tmpFile.Name(), nil
+}
+
+// convertToStdout renders the single selected page and writes the encoded
+// image directly to stdout. On any error it exits the process itself since
+// there is no Result to report alongside a streamed image. The page selection is
+// checked up front, before anything is written, so a selection of more
+// than one page is rejected cleanly instead of partially streaming to
+// stdout.
+func convertToStdout(conv *converter.Converter, inputFile string, dpi int, pages, format string, quality int) {
+	pdfData, err := os.ReadFile(inputFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to open PDF file: %v\n", err)
+		os.Exit(ExitInputNotFound)
+	}
+
+	n, err := conv.ResolvedPageCount(pdfData, pages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitRenderFailed)
+	}
+	if n != 1 {
+		fmt.Fprintf(os.Stderr, "Error: -o - requires a single selected page, but \"%s\" selects %d\n", pages, n)
+		os.Exit(ExitRenderFailed)
+	}
+
+	err = conv.ConvertStream(context.Background(), bytes.NewReader(pdfData), dpi, pages, func(pageNum int, img image.Image) error {
+		return converter.EncodeImage(os.Stdout, img, format, quality)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(ExitRenderFailed)
+	}
 }
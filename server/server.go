@@ -0,0 +1,376 @@
+// Package server exposes Converter over HTTP so a PDFium pool can be kept
+// warm across many requests instead of paying webassembly.Init's startup
+// cost per conversion.
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "embed"
+
+	"github.com/potatoqualitee/pdf2img/converter"
+)
+
+const (
+	defaultMaxUploadBytes = 64 << 20 // 64 MiB
+	defaultMaxPages       = 200
+	defaultTimeout        = 60 * time.Second
+)
+
+//go:embed testdata/healthz.pdf
+var healthzPDF []byte
+
+// Config controls the resource limits and network address of a Server.
+type Config struct {
+	// Addr is the address ListenAndServe binds, e.g. ":8080".
+	Addr string
+
+	// MaxUploadBytes caps the size of an uploaded PDF. Zero uses a
+	// built-in default.
+	MaxUploadBytes int64
+
+	// MaxPages rejects documents with more pages than this. Zero uses a
+	// built-in default.
+	MaxPages int
+
+	// RequestTimeout bounds how long a single /convert call may run.
+	// Zero uses a built-in default.
+	RequestTimeout time.Duration
+}
+
+// Server wraps a Converter behind HTTP handlers. Its PDFium pool is sized to
+// runtime.NumCPU() and stays warm for the life of the process.
+type Server struct {
+	conv *converter.Converter
+	cfg  Config
+}
+
+// New creates a Server and its underlying Converter pool. Call Close when
+// done to release the pool.
+func New(cfg Config) (*Server, error) {
+	if cfg.MaxUploadBytes <= 0 {
+		cfg.MaxUploadBytes = defaultMaxUploadBytes
+	}
+	if cfg.MaxPages <= 0 {
+		cfg.MaxPages = defaultMaxPages
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = defaultTimeout
+	}
+
+	conv, err := converter.New(runtime.NumCPU())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize converter: %w", err)
+	}
+
+	return &Server{conv: conv, cfg: cfg}, nil
+}
+
+// Close releases the underlying PDFium pool.
+func (s *Server) Close() {
+	s.conv.Close()
+}
+
+// Handler builds the server's http.Handler, exposing POST /convert and
+// GET /healthz.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/convert", s.handleConvert)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	return mux
+}
+
+// ListenAndServe starts serving on cfg.Addr. It blocks until the server
+// stops, like http.Server.ListenAndServe.
+func (s *Server) ListenAndServe() error {
+	return http.ListenAndServe(s.cfg.Addr, s.Handler())
+}
+
+// handleConvert accepts a PDF (multipart upload, JSON base64, or JSON URL),
+// converts it per the request's query parameters, and streams the results
+// back as a ZIP, NDJSON, or multipart response.
+func (s *Server) handleConvert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.RequestTimeout)
+	defer cancel()
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.cfg.MaxUploadBytes)
+
+	pdfData, err := readPDFInput(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read PDF input: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	totalPages, err := s.conv.PageCount(pdfData)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid PDF: %v", err), http.StatusBadRequest)
+		return
+	}
+	if totalPages > s.cfg.MaxPages {
+		http.Error(w, fmt.Sprintf("document has %d pages, exceeding the limit of %d", totalPages, s.cfg.MaxPages), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	cfg, err := configFromQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pdf2img-server-*")
+	if err != nil {
+		http.Error(w, "failed to allocate temp directory", http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPDF := filepath.Join(tmpDir, "input.pdf")
+	if err := os.WriteFile(tmpPDF, pdfData, 0644); err != nil {
+		http.Error(w, "failed to buffer upload", http.StatusInternalServerError)
+		return
+	}
+
+	cfg.InputFile = tmpPDF
+	cfg.OutputDir = filepath.Join(tmpDir, "out")
+	cfg.Prefix = "page"
+
+	result, err := s.conv.Convert(ctx, cfg)
+	if err != nil || !result.Success {
+		http.Error(w, fmt.Sprintf("conversion failed: %s", result.Error), http.StatusUnprocessableEntity)
+		return
+	}
+
+	switch r.URL.Query().Get("response") {
+	case "ndjson":
+		writeNDJSONResponse(w, result.OutputFiles)
+	case "multipart":
+		writeMultipartResponse(w, result.OutputFiles)
+	default:
+		writeZIPResponse(w, result.OutputFiles)
+	}
+}
+
+// handleHealthz renders a tiny embedded PDF end to end to confirm the
+// PDFium pool is still alive, rather than just checking the process is up.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	tmpDir, err := os.MkdirTemp("", "pdf2img-healthz-*")
+	if err != nil {
+		http.Error(w, "unhealthy: failed to allocate temp directory", http.StatusServiceUnavailable)
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPDF := filepath.Join(tmpDir, "healthz.pdf")
+	if err := os.WriteFile(tmpPDF, healthzPDF, 0644); err != nil {
+		http.Error(w, "unhealthy: failed to write probe PDF", http.StatusServiceUnavailable)
+		return
+	}
+
+	_, err = s.conv.Convert(ctx, converter.Config{
+		InputFile: tmpPDF,
+		OutputDir: tmpDir,
+		Format:    "png",
+		DPI:       72,
+		Pages:     "1",
+		Prefix:    "healthz",
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unhealthy: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
+
+// readPDFInput pulls the PDF bytes out of either a multipart upload (field
+// "file") or a JSON body with a "pdf_base64" or "pdf_url" field.
+func readPDFInput(r *http.Request) ([]byte, error) {
+	contentType := r.Header.Get("Content-Type")
+
+	if isMultipart(contentType) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("missing \"file\" form field: %w", err)
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+
+	var body struct {
+		PDFBase64 string `json:"pdf_base64"`
+		PDFURL    string `json:"pdf_url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("expected a multipart upload or a JSON body with pdf_base64/pdf_url: %w", err)
+	}
+
+	switch {
+	case body.PDFBase64 != "":
+		data, err := base64.StdEncoding.DecodeString(body.PDFBase64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pdf_base64: %w", err)
+		}
+		return data, nil
+	case body.PDFURL != "":
+		resp, err := http.Get(body.PDFURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pdf_url: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("pdf_url returned status %d", resp.StatusCode)
+		}
+		return io.ReadAll(resp.Body)
+	default:
+		return nil, fmt.Errorf("request must provide a \"file\" upload, pdf_base64, or pdf_url")
+	}
+}
+
+func isMultipart(contentType string) bool {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != ""
+}
+
+// configFromQuery builds a converter.Config from query parameters mirroring
+// Config's fields; InputFile, OutputDir, and Prefix are filled in by the
+// caller once the upload is buffered to disk.
+func configFromQuery(q map[string][]string) (converter.Config, error) {
+	get := func(key, fallback string) string {
+		if v, ok := q[key]; ok && len(v) > 0 && v[0] != "" {
+			return v[0]
+		}
+		return fallback
+	}
+
+	format := get("format", "png")
+	if err := converter.ValidateFormat(format); err != nil {
+		return converter.Config{}, err
+	}
+
+	pages := get("pages", "all")
+	if err := converter.ValidatePageRange(pages); err != nil {
+		return converter.Config{}, err
+	}
+
+	dpi, err := strconv.Atoi(get("dpi", "150"))
+	if err != nil || dpi < 72 || dpi > 600 {
+		return converter.Config{}, fmt.Errorf("dpi must be an integer between 72 and 600")
+	}
+
+	quality, err := strconv.Atoi(get("quality", "85"))
+	if err != nil || quality < 1 || quality > 100 {
+		return converter.Config{}, fmt.Errorf("quality must be an integer between 1 and 100")
+	}
+
+	return converter.Config{
+		Format:  format,
+		Pages:   pages,
+		DPI:     dpi,
+		Quality: quality,
+	}, nil
+}
+
+func writeZIPResponse(w http.ResponseWriter, files []string) {
+	w.Header().Set("Content-Type", "application/zip")
+	zw := zip.NewWriter(w)
+	defer zw.Close()
+
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		entry, err := zw.Create(filepath.Base(f))
+		if err != nil {
+			continue
+		}
+		entry.Write(data)
+	}
+}
+
+func writeNDJSONResponse(w http.ResponseWriter, files []string) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		enc.Encode(struct {
+			File          string `json:"file"`
+			ContentBase64 string `json:"content_base64"`
+		}{
+			File:          filepath.Base(f),
+			ContentBase64: base64.StdEncoding.EncodeToString(data),
+		})
+	}
+}
+
+func writeMultipartResponse(w http.ResponseWriter, files []string) {
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", mw.FormDataContentType())
+	defer mw.Close()
+
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, "page", filepath.Base(f)))
+		header.Set("Content-Type", contentTypeForExt(filepath.Ext(f)))
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			continue
+		}
+		part.Write(data)
+	}
+}
+
+func contentTypeForExt(ext string) string {
+	switch ext {
+	case ".png":
+		return "image/png"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".webp":
+		return "image/webp"
+	case ".tiff":
+		return "image/tiff"
+	case ".txt":
+		return "text/plain"
+	case ".hocr":
+		return "text/html"
+	case ".pdf":
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}